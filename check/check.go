@@ -1,23 +1,14 @@
 package check
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"log/slog"
-	"net/http"
 	"strings"
-	"time"
 )
 
 var apiURL string
 
-const (
-	timeoutSec = 30
-	gapMinutes = 30
-	startHour  = 18
-	startMin   = 0
-)
+const timeoutSec = 30
 
 func SetAPIURL(url string) {
 	apiURL = url
@@ -65,32 +56,18 @@ type APIResponse struct {
 	InspectionData      []InspectionData     `json:"inspection_data"`
 }
 
+// CheckVehicle fetches the RTOC report for registration using the default
+// client and no deadline. Prefer CheckVehicleCtx where a context is
+// available, e.g. so scheduler runs and /check handlers can be cancelled
+// cleanly when the bot shuts down.
 func CheckVehicle(registration string) (*APIResponse, error) {
-	payload, err := json.Marshal(map[string]string{"vehicle": registration})
-	if err != nil {
-		return nil, fmt.Errorf("marshal payload: %w", err)
-	}
-
-	client := &http.Client{Timeout: time.Duration(timeoutSec) * time.Second}
-
-	resp, err := client.Post(apiURL, "application/json", bytes.NewReader(payload))
-	if err != nil {
-		return nil, fmt.Errorf("post request: %w", err)
-	}
-	defer resp.Body.Close()
+	return CheckVehicleCtx(context.Background(), registration)
+}
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		var result APIResponse
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return nil, fmt.Errorf("decode response: %w", err)
-		}
-		return &result, nil
-	case http.StatusTooManyRequests:
-		return nil, fmt.Errorf("rate limited (429): too many requests, try again later")
-	default:
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
+// CheckVehicleCtx fetches the RTOC report for registration, honoring ctx
+// cancellation across retries and rate-limit waits.
+func CheckVehicleCtx(ctx context.Context, registration string) (*APIResponse, error) {
+	return defaultClient.Do(ctx, registration)
 }
 
 func FormatResult(registration string, data *APIResponse) string {
@@ -148,67 +125,12 @@ func FormatResult(registration string, data *APIResponse) string {
 	return sb.String()
 }
 
-func ParseVehicles(vehicles string) []string {
-	parts := strings.Split(vehicles, ",")
-	result := make([]string, 0, len(parts))
-	for _, p := range parts {
-		v := strings.TrimSpace(p)
-		if v != "" {
-			result = append(result, strings.ToUpper(v))
-		}
-	}
-	return result
-}
-
-type NotifyFunc func(text string) error
-
-func StartScheduler(vehicles []string, notify NotifyFunc) {
-	eat := time.FixedZone("EAT", 3*60*60)
-	slog.Info("scheduler started", "vehicles", vehicles, "start_time", fmt.Sprintf("%02d:%02d EAT", startHour, startMin))
-
-	for {
-		now := time.Now().In(eat)
-		next := time.Date(now.Year(), now.Month(), now.Day(), startHour, startMin, 0, 0, eat)
-
-		if now.After(next) {
-			next = next.Add(24 * time.Hour)
-		}
-
-		waitDuration := time.Until(next)
-		slog.Info("next scheduled run", "at", next.Format("2006-01-02 15:04:05"), "in", waitDuration.Round(time.Second))
-
-		time.Sleep(waitDuration)
-
-		checkAllVehicles(vehicles, notify)
-	}
-}
-
-func checkAllVehicles(vehicles []string, notify NotifyFunc) {
-	slog.Info("starting daily vehicle check", "count", len(vehicles))
-
-	for i, reg := range vehicles {
-		if i > 0 {
-			slog.Info("waiting before next vehicle", "gap", fmt.Sprintf("%d minutes", gapMinutes), "next", reg)
-			time.Sleep(time.Duration(gapMinutes) * time.Minute)
-		}
-
-		slog.Info("checking vehicle", "registration", reg)
-
-		data, err := CheckVehicle(reg)
-		if err != nil {
-			slog.Error("failed to check vehicle", "registration", reg, "err", err)
-			errMsg := fmt.Sprintf("❌ Failed to check *%s*: %s", reg, err.Error())
-			if notifyErr := notify(errMsg); notifyErr != nil {
-				slog.Error("failed to send error notification", "err", notifyErr)
-			}
-			continue
-		}
-
-		msg := FormatResult(reg, data)
-		if err := notify(msg); err != nil {
-			slog.Error("failed to send notification", "registration", reg, "err", err)
-		}
-	}
-
-	slog.Info("daily vehicle check completed")
+// Registry is the subscription and snapshot lookup StartScheduler needs.
+// *store.Store satisfies this; it is declared here (rather than imported) so
+// check stays free of a dependency on the store package.
+type Registry interface {
+	AllRegistrations() ([]string, error)
+	SubscribersFor(registration string) ([]int64, error)
+	LastSnapshot(registration string) (*APIResponse, error)
+	SaveSnapshot(registration string, data *APIResponse) error
 }
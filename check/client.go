@@ -0,0 +1,202 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Hopertz/rtocBot/internal/metrics"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultMaxRetries  = 3
+	defaultBaseBackoff = 500 * time.Millisecond
+	defaultMaxBackoff  = 15 * time.Second
+	defaultJitterFrac  = 0.5
+	defaultRateLimit   = rate.Limit(1) // one request per second to the upstream API
+	defaultBurst       = 1
+)
+
+// Client is a configurable RTOC API client with retry, backoff, jitter, and
+// per-host rate limiting, since the upstream API is flaky under load.
+type Client struct {
+	HTTPClient *http.Client
+
+	// MaxRetries is the number of retries attempted after the initial
+	// request, for 5xx responses, network errors, and 429s.
+	MaxRetries int
+	// BaseBackoff and MaxBackoff bound the exponential backoff applied
+	// between retries.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// JitterFrac is the fraction (0-1) of each backoff step that is
+	// randomized, to avoid every retrying client waking up in lockstep.
+	JitterFrac float64
+
+	// Limiter caps outbound request rate to the RTOC API. Nil disables
+	// rate limiting.
+	Limiter *rate.Limiter
+}
+
+// NewClient returns a Client configured with sane defaults for the RTOC API.
+func NewClient() *Client {
+	return &Client{
+		HTTPClient:  &http.Client{Timeout: time.Duration(timeoutSec) * time.Second},
+		MaxRetries:  defaultMaxRetries,
+		BaseBackoff: defaultBaseBackoff,
+		MaxBackoff:  defaultMaxBackoff,
+		JitterFrac:  defaultJitterFrac,
+		Limiter:     rate.NewLimiter(defaultRateLimit, defaultBurst),
+	}
+}
+
+var defaultClient = NewClient()
+
+// SetClient replaces the client used by CheckVehicle and CheckVehicleCtx.
+func SetClient(c *Client) {
+	defaultClient = c
+}
+
+// Do posts the vehicle check request for registration, retrying on 429s and
+// 5xx/network errors with jittered exponential backoff (see backoff). 4xx
+// responses other than 429 fail fast without retrying.
+func (c *Client) Do(ctx context.Context, registration string) (apiResp *APIResponse, err error) {
+	start := time.Now()
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "error"
+		}
+		metrics.ChecksTotal.WithLabelValues(registration, result).Inc()
+		metrics.CheckDuration.WithLabelValues(registration).Observe(time.Since(start).Seconds())
+	}()
+
+	payload, err := json.Marshal(map[string]string{"vehicle": registration})
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	var lastErr error
+	var wait time.Duration
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if wait == 0 {
+				wait = c.backoff(attempt)
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+		}
+
+		if c.Limiter != nil {
+			if err := c.Limiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("rate limiter: %w", err)
+			}
+		}
+
+		resp, retryWait, attemptErr := c.attempt(ctx, payload)
+		if attemptErr == nil {
+			return resp, nil
+		}
+
+		lastErr = attemptErr
+		if !errors.Is(attemptErr, errRetryable) {
+			return nil, attemptErr
+		}
+
+		slog.Warn("check vehicle request failed, retrying", "registration", registration, "attempt", attempt+1, "err", attemptErr)
+		wait = retryWait
+	}
+
+	return nil, fmt.Errorf("giving up after %d attempts: %w", c.MaxRetries+1, lastErr)
+}
+
+// errRetryable marks errors from attempt that are worth retrying.
+var errRetryable = fmt.Errorf("retryable")
+
+func (c *Client) attempt(ctx context.Context, payload []byte) (*APIResponse, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, 0, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: post request: %v", errRetryable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		var result APIResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, 0, fmt.Errorf("decode response: %w", err)
+		}
+		return &result, 0, nil
+
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, retryAfter(resp.Header), fmt.Errorf("%w: rate limited (429): too many requests", errRetryable)
+
+	case resp.StatusCode >= 500:
+		return nil, 0, fmt.Errorf("%w: unexpected status code: %d", errRetryable, resp.StatusCode)
+
+	default:
+		return nil, 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+}
+
+// backoff returns the wait before the given retry attempt (1-indexed): the
+// capped exponential backoff with its top JitterFrac randomized, so a
+// JitterFrac of 1 gives full jitter and 0 gives no jitter.
+func (c *Client) backoff(attempt int) time.Duration {
+	capped := c.BaseBackoff * time.Duration(uint64(1)<<uint(attempt-1))
+	if capped <= 0 || capped > c.MaxBackoff {
+		capped = c.MaxBackoff
+	}
+
+	jitterRange := time.Duration(float64(capped) * c.JitterFrac)
+	floor := capped - jitterRange
+	if jitterRange <= 0 {
+		return floor
+	}
+	return floor + time.Duration(rand.Int63n(int64(jitterRange)+1))
+}
+
+// retryAfter parses the Retry-After header as either delay-seconds or an
+// HTTP-date, returning 0 if absent or unparseable.
+func retryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,72 @@
+package check
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClientBackoffBoundsAndJitter(t *testing.T) {
+	c := &Client{
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  15 * time.Second,
+		JitterFrac:  0.5,
+	}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		d := c.backoff(attempt)
+		if d <= 0 {
+			t.Fatalf("backoff(%d) = %v, want > 0", attempt, d)
+		}
+		if d > c.MaxBackoff {
+			t.Fatalf("backoff(%d) = %v, want <= MaxBackoff %v", attempt, d, c.MaxBackoff)
+		}
+	}
+}
+
+func TestClientBackoffZeroJitterIsDeterministic(t *testing.T) {
+	c := &Client{
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  15 * time.Second,
+		JitterFrac:  0,
+	}
+
+	want := 2 * time.Second // BaseBackoff << (attempt-1) for attempt=3
+	if got := c.backoff(3); got != want {
+		t.Fatalf("backoff(3) = %v, want %v", got, want)
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	h := http.Header{}
+	h.Set("Retry-After", "5")
+
+	got := retryAfter(h)
+	want := 5 * time.Second
+	if got != want {
+		t.Fatalf("retryAfter() = %v, want %v", got, want)
+	}
+}
+
+func TestRetryAfterHTTPDate(t *testing.T) {
+	future := time.Now().Add(10 * time.Second).UTC()
+	h := http.Header{}
+	h.Set("Retry-After", future.Format(http.TimeFormat))
+
+	got := retryAfter(h)
+	if got <= 0 || got > 11*time.Second {
+		t.Fatalf("retryAfter() = %v, want ~10s", got)
+	}
+}
+
+func TestRetryAfterMissingOrInvalid(t *testing.T) {
+	if got := retryAfter(http.Header{}); got != 0 {
+		t.Fatalf("retryAfter(empty) = %v, want 0", got)
+	}
+
+	h := http.Header{}
+	h.Set("Retry-After", "not-a-number-or-date")
+	if got := retryAfter(h); got != 0 {
+		t.Fatalf("retryAfter(invalid) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,75 @@
+package check
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduleEntry is a parsed SchedulerConfig.Times entry: a time of day,
+// optionally restricted to a single day of week.
+type scheduleEntry struct {
+	hour    int
+	minute  int
+	weekday *time.Weekday // nil means every day
+}
+
+// parseScheduleSpec parses a single --schedule entry, either a plain
+// "HH:MM" time or a 5-field cron expression ("M H * * *" / "M H * * DOW"),
+// e.g. "18:00" or "0 18 * * *" or "0 6 * * 1" (Mondays at 06:00). Minute
+// and hour must be literal integers, day-of-week is "*" or a literal
+// integer 0-6, and day-of-month and month must be "*".
+func parseScheduleSpec(spec string) (scheduleEntry, error) {
+	spec = strings.TrimSpace(spec)
+
+	if !strings.Contains(spec, " ") {
+		t, err := time.Parse("15:04", spec)
+		if err != nil {
+			return scheduleEntry{}, fmt.Errorf("parse time %q: %w", spec, err)
+		}
+		return scheduleEntry{hour: t.Hour(), minute: t.Minute()}, nil
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return scheduleEntry{}, fmt.Errorf("cron expression %q must have 5 fields", spec)
+	}
+
+	minute, err := parseCronNumber(fields[0], 0, 59)
+	if err != nil {
+		return scheduleEntry{}, fmt.Errorf("minute field: %w", err)
+	}
+	hour, err := parseCronNumber(fields[1], 0, 23)
+	if err != nil {
+		return scheduleEntry{}, fmt.Errorf("hour field: %w", err)
+	}
+	if fields[2] != "*" {
+		return scheduleEntry{}, fmt.Errorf("day-of-month field %q is unsupported, only \"*\"", fields[2])
+	}
+	if fields[3] != "*" {
+		return scheduleEntry{}, fmt.Errorf("month field %q is unsupported, only \"*\"", fields[3])
+	}
+
+	entry := scheduleEntry{hour: hour, minute: minute}
+	if fields[4] != "*" {
+		dow, err := parseCronNumber(fields[4], 0, 6)
+		if err != nil {
+			return scheduleEntry{}, fmt.Errorf("day-of-week field: %w", err)
+		}
+		weekday := time.Weekday(dow)
+		entry.weekday = &weekday
+	}
+	return entry, nil
+}
+
+func parseCronNumber(field string, min, max int) (int, error) {
+	v, err := strconv.Atoi(field)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q, expected an integer", field)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value %d out of range [%d,%d]", v, min, max)
+	}
+	return v, nil
+}
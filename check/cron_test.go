@@ -0,0 +1,87 @@
+package check
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleSpecPlainTime(t *testing.T) {
+	entry, err := parseScheduleSpec("18:05")
+	if err != nil {
+		t.Fatalf("parseScheduleSpec() error = %v", err)
+	}
+	if entry.hour != 18 || entry.minute != 5 || entry.weekday != nil {
+		t.Fatalf("entry = %+v, want hour=18 minute=5 weekday=nil", entry)
+	}
+}
+
+func TestParseScheduleSpecCronEveryDay(t *testing.T) {
+	entry, err := parseScheduleSpec("0 18 * * *")
+	if err != nil {
+		t.Fatalf("parseScheduleSpec() error = %v", err)
+	}
+	if entry.hour != 18 || entry.minute != 0 || entry.weekday != nil {
+		t.Fatalf("entry = %+v, want hour=18 minute=0 weekday=nil", entry)
+	}
+}
+
+func TestParseScheduleSpecCronWithWeekday(t *testing.T) {
+	entry, err := parseScheduleSpec("0 6 * * 1")
+	if err != nil {
+		t.Fatalf("parseScheduleSpec() error = %v", err)
+	}
+	if entry.weekday == nil || *entry.weekday != time.Monday {
+		t.Fatalf("entry.weekday = %v, want Monday", entry.weekday)
+	}
+}
+
+func TestParseScheduleSpecRejectsUnsupportedFields(t *testing.T) {
+	cases := []string{
+		"0 18 1 * *",  // day-of-month must be *
+		"0 18 * 6 *",  // month must be *
+		"0 18 * *",    // wrong field count
+		"60 18 * * *", // minute out of range
+		"0 24 * * *",  // hour out of range
+		"0 18 * * 7",  // weekday out of range
+		"x 18 * * *",  // not an integer
+	}
+	for _, spec := range cases {
+		if _, err := parseScheduleSpec(spec); err == nil {
+			t.Errorf("parseScheduleSpec(%q) error = nil, want error", spec)
+		}
+	}
+}
+
+func TestSchedulerConfigNextRunsOrderedAndFuture(t *testing.T) {
+	cfg := SchedulerConfig{Times: []string{"06:00", "18:00"}, Timezone: "EAT"}
+	from := time.Date(2026, 7, 26, 12, 0, 0, 0, time.UTC)
+
+	runs, err := cfg.NextRuns(from, 3)
+	if err != nil {
+		t.Fatalf("NextRuns() error = %v", err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("len(runs) = %d, want 3", len(runs))
+	}
+	for i, run := range runs {
+		if !run.After(from) {
+			t.Errorf("runs[%d] = %v, want after %v", i, run, from)
+		}
+		if i > 0 && !run.After(runs[i-1]) {
+			t.Errorf("runs[%d] = %v, want after runs[%d] = %v", i, run, i-1, runs[i-1])
+		}
+	}
+}
+
+func TestSchedulerConfigNextRunsWeekdayFilter(t *testing.T) {
+	cfg := SchedulerConfig{Times: []string{"0 6 * * 1"}, Timezone: "EAT"}
+	from := time.Date(2026, 7, 26, 0, 0, 0, 0, time.UTC) // a Sunday
+
+	runs, err := cfg.NextRuns(from, 1)
+	if err != nil {
+		t.Fatalf("NextRuns() error = %v", err)
+	}
+	if runs[0].Weekday() != time.Monday {
+		t.Fatalf("runs[0].Weekday() = %v, want Monday", runs[0].Weekday())
+	}
+}
@@ -0,0 +1,129 @@
+package check
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StatusChange records an existing pending offence whose status moved
+// between two checks (e.g. unpaid -> paid).
+type StatusChange struct {
+	Reference string
+	Offence   string
+	From      string
+	To        string
+}
+
+// ResponseDiff is the set of changes between two APIResponse snapshots for
+// the same registration.
+type ResponseDiff struct {
+	NewOffences      []PendingTransaction
+	ResolvedOffences []PendingTransaction
+	StatusChanges    []StatusChange
+	NewInspections   []InspectionData
+}
+
+// IsEmpty reports whether the diff contains nothing worth notifying about.
+func (d *ResponseDiff) IsEmpty() bool {
+	return len(d.NewOffences) == 0 && len(d.ResolvedOffences) == 0 &&
+		len(d.StatusChanges) == 0 && len(d.NewInspections) == 0
+}
+
+// DiffResponse compares prev and curr and reports new offences, offences
+// that disappeared (resolved), offences whose status changed, and new
+// inspection records. Offences are matched by PendingTransaction.Reference
+// and inspections by InspectionData.ID.
+func DiffResponse(prev, curr *APIResponse) *ResponseDiff {
+	diff := &ResponseDiff{}
+
+	prevByRef := make(map[string]PendingTransaction, len(prev.PendingTransactions))
+	for _, txn := range prev.PendingTransactions {
+		prevByRef[txn.Reference] = txn
+	}
+
+	currByRef := make(map[string]PendingTransaction, len(curr.PendingTransactions))
+	for _, txn := range curr.PendingTransactions {
+		currByRef[txn.Reference] = txn
+
+		old, existed := prevByRef[txn.Reference]
+		if !existed {
+			diff.NewOffences = append(diff.NewOffences, txn)
+			continue
+		}
+		if old.Status != txn.Status {
+			diff.StatusChanges = append(diff.StatusChanges, StatusChange{
+				Reference: txn.Reference,
+				Offence:   txn.Offence,
+				From:      old.Status,
+				To:        txn.Status,
+			})
+		}
+	}
+
+	for ref, txn := range prevByRef {
+		if _, stillPending := currByRef[ref]; !stillPending {
+			diff.ResolvedOffences = append(diff.ResolvedOffences, txn)
+		}
+	}
+
+	prevInspections := make(map[int]struct{}, len(prev.InspectionData))
+	for _, ins := range prev.InspectionData {
+		prevInspections[ins.ID] = struct{}{}
+	}
+	for _, ins := range curr.InspectionData {
+		if _, seen := prevInspections[ins.ID]; !seen {
+			diff.NewInspections = append(diff.NewInspections, ins)
+		}
+	}
+
+	return diff
+}
+
+// FormatDiff renders a ResponseDiff as a Telegram Markdown message, covering
+// only what changed since the previous check.
+func FormatDiff(registration string, diff *ResponseDiff) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "🚗 *RTOC Update for %s*\n", registration)
+	fmt.Fprintf(&sb, "━━━━━━━━━━━━━━━━━━━━━\n")
+
+	if diff.IsEmpty() {
+		fmt.Fprintf(&sb, "✅ No changes since the last check.\n")
+		return sb.String()
+	}
+
+	if len(diff.NewOffences) > 0 {
+		fmt.Fprintf(&sb, "🆕 *New offences (%d)*\n\n", len(diff.NewOffences))
+		for i, txn := range diff.NewOffences {
+			fmt.Fprintf(&sb, "*%d.* %s\n", i+1, txn.Offence)
+			fmt.Fprintf(&sb, "   📍 %s\n", txn.Location)
+			fmt.Fprintf(&sb, "   💰 Charge: %s | Penalty: %s\n", txn.Charge, txn.Penalty)
+			fmt.Fprintf(&sb, "   🔖 Ref: %s\n\n", txn.Reference)
+		}
+	}
+
+	if len(diff.ResolvedOffences) > 0 {
+		fmt.Fprintf(&sb, "✅ *Resolved offences (%d)*\n\n", len(diff.ResolvedOffences))
+		for i, txn := range diff.ResolvedOffences {
+			fmt.Fprintf(&sb, "*%d.* %s (Ref: %s)\n", i+1, txn.Offence, txn.Reference)
+		}
+		fmt.Fprintf(&sb, "\n")
+	}
+
+	if len(diff.StatusChanges) > 0 {
+		fmt.Fprintf(&sb, "🔁 *Status changed (%d)*\n\n", len(diff.StatusChanges))
+		for i, sc := range diff.StatusChanges {
+			fmt.Fprintf(&sb, "*%d.* %s: %s → %s (Ref: %s)\n", i+1, sc.Offence, sc.From, sc.To, sc.Reference)
+		}
+		fmt.Fprintf(&sb, "\n")
+	}
+
+	if len(diff.NewInspections) > 0 {
+		fmt.Fprintf(&sb, "🔍 *New inspection records (%d)*\n\n", len(diff.NewInspections))
+		for i, ins := range diff.NewInspections {
+			fmt.Fprintf(&sb, "*%d.* %s — *%s*\n", i+1, ins.ReasonEN, ins.FinalResult)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,60 @@
+package check
+
+import "testing"
+
+func TestDiffResponseNewResolvedAndStatusChanged(t *testing.T) {
+	prev := &APIResponse{
+		PendingTransactions: []PendingTransaction{
+			{Reference: "A1", Offence: "speeding", Status: "unpaid"},
+			{Reference: "A2", Offence: "overloading", Status: "unpaid"},
+		},
+		InspectionData: []InspectionData{
+			{ID: 1, ReasonEN: "routine"},
+		},
+	}
+	curr := &APIResponse{
+		PendingTransactions: []PendingTransaction{
+			{Reference: "A1", Offence: "speeding", Status: "paid"},
+			{Reference: "A3", Offence: "no insurance", Status: "unpaid"},
+		},
+		InspectionData: []InspectionData{
+			{ID: 1, ReasonEN: "routine"},
+			{ID: 2, ReasonEN: "follow-up"},
+		},
+	}
+
+	diff := DiffResponse(prev, curr)
+
+	if len(diff.NewOffences) != 1 || diff.NewOffences[0].Reference != "A3" {
+		t.Fatalf("NewOffences = %+v, want [A3]", diff.NewOffences)
+	}
+	if len(diff.ResolvedOffences) != 1 || diff.ResolvedOffences[0].Reference != "A2" {
+		t.Fatalf("ResolvedOffences = %+v, want [A2]", diff.ResolvedOffences)
+	}
+	if len(diff.StatusChanges) != 1 || diff.StatusChanges[0] != (StatusChange{Reference: "A1", Offence: "speeding", From: "unpaid", To: "paid"}) {
+		t.Fatalf("StatusChanges = %+v, want [A1 unpaid->paid]", diff.StatusChanges)
+	}
+	if len(diff.NewInspections) != 1 || diff.NewInspections[0].ID != 2 {
+		t.Fatalf("NewInspections = %+v, want [ID 2]", diff.NewInspections)
+	}
+	if diff.IsEmpty() {
+		t.Fatal("IsEmpty() = true, want false")
+	}
+}
+
+func TestDiffResponseNoChanges(t *testing.T) {
+	resp := &APIResponse{
+		PendingTransactions: []PendingTransaction{
+			{Reference: "A1", Offence: "speeding", Status: "unpaid"},
+		},
+		InspectionData: []InspectionData{
+			{ID: 1, ReasonEN: "routine"},
+		},
+	}
+
+	diff := DiffResponse(resp, resp)
+
+	if !diff.IsEmpty() {
+		t.Fatalf("IsEmpty() = false, want true, diff = %+v", diff)
+	}
+}
@@ -0,0 +1,18 @@
+package check
+
+import "context"
+
+// Attachment is a named blob delivered alongside a notification, e.g. the
+// raw JSON of the APIResponse a report was generated from.
+type Attachment struct {
+	Filename string
+	MIME     string
+	Content  []byte
+}
+
+// Notifier delivers a notification for a single chat/destination. Concrete
+// sinks (Telegram, webhooks, other chat platforms) live under
+// check/notify and implement this interface.
+type Notifier interface {
+	Notify(ctx context.Context, chatID int64, subject, body string, attachments []Attachment) error
+}
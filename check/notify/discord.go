@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Hopertz/rtocBot/check"
+	"github.com/Hopertz/rtocBot/internal/metrics"
+)
+
+// discordContentLimit is Discord's hard cap on a webhook message's content
+// field. Longer reports are split across multiple messages.
+const discordContentLimit = 2000
+
+// DiscordNotifier posts notifications to a single Discord incoming
+// webhook. Discord webhooks are channel-scoped, not chat-scoped, so chatID
+// is ignored and every notification lands in the configured channel.
+type DiscordNotifier struct {
+	WebhookURL string
+	HTTPClient *http.Client
+}
+
+// NewDiscordNotifier returns a DiscordNotifier posting to webhookURL with
+// the default http.Client.
+func NewDiscordNotifier(webhookURL string) *DiscordNotifier {
+	return &DiscordNotifier{WebhookURL: webhookURL, HTTPClient: http.DefaultClient}
+}
+
+type discordPayload struct {
+	Content string `json:"content"`
+}
+
+// Notify posts subject and body as the content of a Discord webhook
+// message. attachments are ignored: Discord webhooks expect plain text.
+func (d *DiscordNotifier) Notify(ctx context.Context, chatID int64, subject, body string, attachments []check.Attachment) (err error) {
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "error"
+		}
+		metrics.NotificationsSentTotal.WithLabelValues("discord", result).Inc()
+	}()
+
+	content := body
+	if subject != "" {
+		content = fmt.Sprintf("**%s**\n%s", subject, body)
+	}
+
+	for _, chunk := range splitDiscordContent(content, discordContentLimit) {
+		if err := d.post(ctx, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *DiscordNotifier) post(ctx context.Context, content string) error {
+	raw, err := json.Marshal(discordPayload{Content: content})
+	if err != nil {
+		return fmt.Errorf("marshal discord payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.WebhookURL, bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("build discord request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post discord webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("discord webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// splitDiscordContent splits content into chunks no longer than limit,
+// breaking on line boundaries where possible so a single offence entry
+// isn't split mid-line.
+func splitDiscordContent(content string, limit int) []string {
+	if len(content) <= limit {
+		return []string{content}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if current.Len() > 0 && current.Len()+len(line) > limit {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		for len(line) > limit {
+			chunks = append(chunks, line[:limit])
+			line = line[limit:]
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitDiscordContentUnderLimit(t *testing.T) {
+	content := "short message"
+	chunks := splitDiscordContent(content, 2000)
+	if len(chunks) != 1 || chunks[0] != content {
+		t.Fatalf("splitDiscordContent() = %v, want [%q]", chunks, content)
+	}
+}
+
+func TestSplitDiscordContentSplitsOnLineBoundaries(t *testing.T) {
+	line := strings.Repeat("a", 30) + "\n"
+	content := strings.Repeat(line, 5) // 155 chars total
+
+	chunks := splitDiscordContent(content, 60)
+
+	if len(chunks) < 2 {
+		t.Fatalf("len(chunks) = %d, want >= 2", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if len(chunk) > 60 {
+			t.Errorf("chunk %d length = %d, want <= 60", i, len(chunk))
+		}
+	}
+	if strings.Join(chunks, "") != content {
+		t.Fatalf("rejoined chunks = %q, want %q", strings.Join(chunks, ""), content)
+	}
+}
+
+func TestSplitDiscordContentSplitsOverlongLine(t *testing.T) {
+	content := strings.Repeat("x", 250)
+
+	chunks := splitDiscordContent(content, 100)
+
+	if len(chunks) != 3 {
+		t.Fatalf("len(chunks) = %d, want 3", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if len(chunk) > 100 {
+			t.Errorf("chunk %d length = %d, want <= 100", i, len(chunk))
+		}
+	}
+	if strings.Join(chunks, "") != content {
+		t.Fatal("rejoined chunks do not match original content")
+	}
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Hopertz/rtocBot/check"
+)
+
+// Multi fans a single notification out to every underlying Notifier,
+// collecting failures instead of stopping at the first one.
+type Multi struct {
+	Notifiers []check.Notifier
+}
+
+// NewMulti returns a Multi wrapping notifiers.
+func NewMulti(notifiers ...check.Notifier) *Multi {
+	return &Multi{Notifiers: notifiers}
+}
+
+// Notify calls Notify on every underlying notifier and joins their errors,
+// if any.
+func (m *Multi) Notify(ctx context.Context, chatID int64, subject, body string, attachments []check.Attachment) error {
+	var errs []error
+	for _, n := range m.Notifiers {
+		if err := n.Notify(ctx, chatID, subject, body, attachments); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,43 @@
+// Package notify provides concrete check.Notifier implementations so the
+// bot can fan RTOC alerts out to Telegram, webhooks, and other chat
+// platforms.
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Hopertz/rtocBot/check"
+	"github.com/Hopertz/rtocBot/internal/metrics"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TelegramNotifier sends notifications as Markdown messages via an existing
+// bot session. chatID is the Telegram chat ID.
+type TelegramNotifier struct {
+	Bot *tgbotapi.BotAPI
+}
+
+// NewTelegramNotifier wraps bot for use as a check.Notifier.
+func NewTelegramNotifier(bot *tgbotapi.BotAPI) *TelegramNotifier {
+	return &TelegramNotifier{Bot: bot}
+}
+
+// Notify sends body as a Markdown message to chatID. subject and
+// attachments are ignored: body is already a fully formatted report.
+func (t *TelegramNotifier) Notify(ctx context.Context, chatID int64, subject, body string, attachments []check.Attachment) error {
+	msg := tgbotapi.NewMessage(chatID, body)
+	msg.ParseMode = "Markdown"
+	_, err := t.Bot.Send(msg)
+
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	metrics.NotificationsSentTotal.WithLabelValues("telegram", result).Inc()
+
+	if err != nil {
+		return fmt.Errorf("send telegram message: %w", err)
+	}
+	return nil
+}
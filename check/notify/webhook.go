@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Hopertz/rtocBot/check"
+	"github.com/Hopertz/rtocBot/internal/metrics"
+)
+
+// WebhookNotifier POSTs a JSON payload to URL for every notification,
+// letting operators pipe RTOC alerts into their own systems.
+type WebhookNotifier struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewWebhookNotifier returns a WebhookNotifier posting to url with the
+// default http.Client.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, HTTPClient: http.DefaultClient}
+}
+
+type webhookPayload struct {
+	ChatID  int64           `json:"chat_id"`
+	Subject string          `json:"subject"`
+	Body    string          `json:"body"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Notify POSTs subject, body, and the raw APIResponse attachment (if
+// present) as JSON to w.URL.
+func (w *WebhookNotifier) Notify(ctx context.Context, chatID int64, subject, body string, attachments []check.Attachment) (err error) {
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "error"
+		}
+		metrics.NotificationsSentTotal.WithLabelValues("webhook", result).Inc()
+	}()
+
+	payload := webhookPayload{ChatID: chatID, Subject: subject, Body: body}
+	for _, a := range attachments {
+		if a.MIME == "application/json" && len(a.Content) > 0 {
+			payload.Data = a.Content
+			break
+		}
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
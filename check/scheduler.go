@@ -0,0 +1,288 @@
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Hopertz/rtocBot/internal/metrics"
+)
+
+// SchedulerConfig controls when StartScheduler runs and how much
+// spacing/concurrency it uses within a run.
+type SchedulerConfig struct {
+	// Times are run times, each either "HH:MM" or a 5-field cron
+	// expression (e.g. "0 18 * * *", or "0 6 * * 1" for Mondays at 06:00),
+	// interpreted in Timezone.
+	Times []string
+	// Timezone is an IANA zone name (e.g. "Africa/Dar_es_Salaam"), or the
+	// literal "EAT" for the fixed UTC+3 offset this bot has always used.
+	// Empty defaults to "EAT".
+	Timezone string
+	// PerVehicleGap spaces out when each registration's check is
+	// dispatched within a run, independent of MaxConcurrent.
+	PerVehicleGap time.Duration
+	// Jitter is the maximum random delay added on top of each scheduled
+	// run time and each PerVehicleGap, so runs aren't triggered at a
+	// perfectly predictable instant.
+	Jitter time.Duration
+	// MaxConcurrent bounds how many registrations checkAllVehicles
+	// processes at once. Defaults to 1 (fully serial) if <= 0.
+	MaxConcurrent int
+}
+
+// DefaultSchedulerConfig mirrors the bot's original fixed 18:00 EAT,
+// 30-minute-gap, fully serial behavior.
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		Times:         []string{"18:00"},
+		Timezone:      "EAT",
+		PerVehicleGap: 30 * time.Minute,
+		MaxConcurrent: 1,
+	}
+}
+
+func (c SchedulerConfig) location() (*time.Location, error) {
+	switch c.Timezone {
+	case "", "EAT":
+		return time.FixedZone("EAT", 3*60*60), nil
+	default:
+		loc, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("load timezone %q: %w", c.Timezone, err)
+		}
+		return loc, nil
+	}
+}
+
+func (c SchedulerConfig) jitter() time.Duration {
+	if c.Jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(c.Jitter) + 1))
+}
+
+// maxScheduleLookaheadDays bounds how far NextRuns searches for upcoming
+// occurrences, so a schedule whose day-of-week filters never align can't
+// spin forever.
+const maxScheduleLookaheadDays = 3650
+
+// NextRuns returns the next n scheduled run times, strictly after from,
+// across all of c.Times, in chronological order. Random jitter is not
+// applied so the result is deterministic: StartScheduler adds jitter when
+// it actually sleeps, and --dry-run uses NextRuns as-is to print the
+// planned schedule for verification.
+func (c SchedulerConfig) NextRuns(from time.Time, n int) ([]time.Time, error) {
+	loc, err := c.location()
+	if err != nil {
+		return nil, err
+	}
+
+	specs := c.Times
+	if len(specs) == 0 {
+		specs = DefaultSchedulerConfig().Times
+	}
+
+	entries := make([]scheduleEntry, 0, len(specs))
+	for _, spec := range specs {
+		entry, err := parseScheduleSpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("schedule entry %q: %w", spec, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	from = from.In(loc)
+
+	runs := make([]time.Time, 0, n)
+	for day := 0; len(runs) < n && day < maxScheduleLookaheadDays; day++ {
+		var todays []time.Time
+		for _, e := range entries {
+			candidate := time.Date(from.Year(), from.Month(), from.Day(), e.hour, e.minute, 0, 0, loc).AddDate(0, 0, day)
+			if e.weekday != nil && candidate.Weekday() != *e.weekday {
+				continue
+			}
+			if !candidate.After(from) {
+				continue
+			}
+			todays = append(todays, candidate)
+		}
+		sort.Slice(todays, func(i, j int) bool { return todays[i].Before(todays[j]) })
+
+		for _, t := range todays {
+			runs = append(runs, t)
+			if len(runs) == n {
+				break
+			}
+		}
+	}
+
+	if len(runs) < n {
+		return nil, fmt.Errorf("could not find %d upcoming run(s) for schedule %v within %d days", n, specs, maxScheduleLookaheadDays)
+	}
+	return runs, nil
+}
+
+// StartScheduler runs checkAllVehicles at each time in cfg.Times (plus
+// jitter), until ctx is cancelled (e.g. on SIGINT/SIGTERM), so a shutdown
+// never leaves it mid-wait indefinitely. health, if non-nil, is updated
+// after every run so /healthz and /readyz reflect scheduler liveness.
+func StartScheduler(ctx context.Context, cfg SchedulerConfig, registry Registry, notifier Notifier, health *metrics.Health) {
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = 1
+	}
+
+	for {
+		if ctx.Err() != nil {
+			slog.Info("scheduler stopping", "reason", ctx.Err())
+			return
+		}
+
+		runs, err := cfg.NextRuns(time.Now(), 1)
+		if err != nil {
+			slog.Error("failed to compute next scheduled run", "err", err)
+			if err := sleep(ctx, time.Minute); err != nil {
+				slog.Info("scheduler stopping", "reason", err)
+				return
+			}
+			continue
+		}
+
+		next := runs[0].Add(cfg.jitter())
+		wait := time.Until(next)
+		slog.Info("next scheduled run", "at", next.Format("2006-01-02 15:04:05 MST"), "in", wait.Round(time.Second))
+
+		if err := sleep(ctx, wait); err != nil {
+			slog.Info("scheduler stopping", "reason", err)
+			return
+		}
+
+		checkAllVehicles(ctx, cfg, registry, notifier, health)
+	}
+}
+
+// checkAllVehicles checks every subscribed registration, bounded to
+// cfg.MaxConcurrent in flight at once, and notifies each registration's
+// subscribers. It stops dispatching new checks as soon as ctx is cancelled,
+// though already-dispatched checks are allowed to finish.
+func checkAllVehicles(ctx context.Context, cfg SchedulerConfig, registry Registry, notifier Notifier, health *metrics.Health) {
+	vehicles, err := registry.AllRegistrations()
+	if err != nil {
+		slog.Error("failed to load subscribed registrations", "err", err)
+		if health != nil {
+			health.RecordRun(err)
+		}
+		return
+	}
+
+	slog.Info("starting scheduled vehicle check", "count", len(vehicles), "max_concurrent", cfg.MaxConcurrent)
+
+	sem := make(chan struct{}, cfg.MaxConcurrent)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var runErr error
+
+	for i, reg := range vehicles {
+		if i > 0 && cfg.PerVehicleGap > 0 {
+			if err := sleep(ctx, cfg.PerVehicleGap+cfg.jitter()); err != nil {
+				slog.Info("scheduled vehicle check interrupted", "reason", err)
+				break
+			}
+		}
+		if ctx.Err() != nil {
+			slog.Info("scheduled vehicle check interrupted", "reason", ctx.Err())
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(reg string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := checkOneVehicle(ctx, reg, registry, notifier); err != nil {
+				mu.Lock()
+				if runErr == nil {
+					runErr = err
+				}
+				mu.Unlock()
+			}
+		}(reg)
+	}
+
+	wg.Wait()
+	if health != nil {
+		health.RecordRun(runErr)
+	}
+	slog.Info("scheduled vehicle check completed")
+}
+
+// checkOneVehicle checks a single registration and notifies its
+// subscribers, returning the check error (if any) for liveness reporting.
+func checkOneVehicle(ctx context.Context, reg string, registry Registry, notifier Notifier) error {
+	slog.Info("checking vehicle", "registration", reg)
+
+	subscribers, err := registry.SubscribersFor(reg)
+	if err != nil {
+		slog.Error("failed to load subscribers", "registration", reg, "err", err)
+		return err
+	}
+
+	data, err := CheckVehicleCtx(ctx, reg)
+	if err != nil {
+		slog.Error("failed to check vehicle", "registration", reg, "err", err)
+		errMsg := fmt.Sprintf("❌ Failed to check *%s*: %s", reg, err.Error())
+		notifyAll(ctx, subscribers, reg, errMsg, nil, notifier)
+		return err
+	}
+
+	metrics.PendingOffences.WithLabelValues(reg).Set(float64(len(data.PendingTransactions)))
+	metrics.InspectionRecords.WithLabelValues(reg).Set(float64(len(data.InspectionData)))
+
+	attachments := []Attachment{rawAttachment(data)}
+
+	prev, err := registry.LastSnapshot(reg)
+	if err != nil {
+		slog.Error("failed to load previous snapshot", "registration", reg, "err", err)
+	}
+
+	if prev != nil {
+		diff := DiffResponse(prev, data)
+		if !diff.IsEmpty() {
+			notifyAll(ctx, subscribers, reg, FormatDiff(reg, diff), attachments, notifier)
+		} else {
+			slog.Info("no changes since last check, skipping notification", "registration", reg)
+		}
+	} else {
+		notifyAll(ctx, subscribers, reg, FormatResult(reg, data), attachments, notifier)
+	}
+
+	if err := registry.SaveSnapshot(reg, data); err != nil {
+		slog.Error("failed to save snapshot", "registration", reg, "err", err)
+	}
+	return nil
+}
+
+func notifyAll(ctx context.Context, chatIDs []int64, registration, body string, attachments []Attachment, notifier Notifier) {
+	subject := fmt.Sprintf("RTOC update: %s", registration)
+	for _, chatID := range chatIDs {
+		if err := notifier.Notify(ctx, chatID, subject, body, attachments); err != nil {
+			slog.Error("failed to send notification", "chat_id", chatID, "err", err)
+		}
+	}
+}
+
+// rawAttachment packages data as JSON so non-chat sinks (webhooks) can act
+// on the full APIResponse, not just the formatted text.
+func rawAttachment(data *APIResponse) Attachment {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		slog.Error("failed to marshal response attachment", "err", err)
+		return Attachment{Filename: "response.json", MIME: "application/json"}
+	}
+	return Attachment{Filename: "response.json", MIME: "application/json", Content: raw}
+}
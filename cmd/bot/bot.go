@@ -1,20 +1,34 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
-	"strconv"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Hopertz/rtocBot/check"
+	"github.com/Hopertz/rtocBot/check/notify"
+	"github.com/Hopertz/rtocBot/internal/metrics"
+	"github.com/Hopertz/rtocBot/store"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 const (
-	start_txt   = "Use /check to check for vehicle road traffic offences or wait for vehicle road traffic offences notifications for listed vehicles. Type /stop to stop receiving notifications`"
+	start_txt = "Use /subscribe <REG> to get road traffic offence notifications for a vehicle, /check <REG> to check it right now, /list to see your subscriptions, /unsubscribe <REG> to remove one, or /stop to pause all notifications."
+	help_txt  = `
+Commands for this @rtocbot are:
+
+/start                start the bot
+/check <REG>          check a specific vehicle right now, e.g. /check T945CEP
+/subscribe <REG>      get daily notifications for a vehicle
+/unsubscribe <REG>    stop notifications for a vehicle
+/list                 list your subscribed vehicles
+/stop                 pause all notifications for this chat`
 	unknown_cmd = "I don't know that command"
 )
 
@@ -29,14 +43,32 @@ func init() {
 func main() {
 
 	var bot_token string
-	var vehicles string
-	var masterIDStr string
 	var apiURL string
+	var dbPath string
+	var notifiers string
+	var webhookURL string
+	var discordWebhookURL string
+	var schedule string
+	var timezone string
+	var perVehicleGap time.Duration
+	var jitter time.Duration
+	var maxConcurrent int
+	var dryRun int
+	var metricsAddr string
 
 	flag.StringVar(&bot_token, "bot-token", os.Getenv("TG_BOT_TOKEN"), "Bot Token")
-	flag.StringVar(&vehicles, "vehicles", os.Getenv("VEHICLES"), "Vehicles")
-	flag.StringVar(&masterIDStr, "master-id", os.Getenv("MASTER_ID"), "Master Chat ID")
 	flag.StringVar(&apiURL, "api-url", os.Getenv("RTOC_API_URL"), "RTOC API URL")
+	flag.StringVar(&dbPath, "db-path", envOrDefault("STORE_PATH", "rtocbot.db"), "Path to the subscription store database")
+	flag.StringVar(&notifiers, "notifier", envOrDefault("NOTIFIERS", "telegram"), "Comma-separated notification sinks to fan alerts out to: telegram,webhook,discord")
+	flag.StringVar(&webhookURL, "webhook-url", os.Getenv("WEBHOOK_URL"), "URL to POST JSON alerts to when --notifier includes webhook")
+	flag.StringVar(&discordWebhookURL, "discord-webhook-url", os.Getenv("DISCORD_WEBHOOK_URL"), "Discord incoming webhook URL when --notifier includes discord")
+	flag.StringVar(&schedule, "schedule", envOrDefault("SCHEDULE", "18:00"), `Comma-separated run times, each "HH:MM" or a 5-field cron expression, e.g. "0 18 * * *,0 6 * * 1"`)
+	flag.StringVar(&timezone, "timezone", envOrDefault("SCHEDULE_TZ", "EAT"), "Timezone the schedule is interpreted in")
+	flag.DurationVar(&perVehicleGap, "per-vehicle-gap", 30*time.Minute, "Minimum spacing between dispatching each vehicle's check within a run")
+	flag.DurationVar(&jitter, "jitter", 0, "Maximum random jitter added to each scheduled run time and per-vehicle gap")
+	flag.IntVar(&maxConcurrent, "max-concurrent", 1, "Maximum number of vehicles checked concurrently within a run")
+	flag.IntVar(&dryRun, "dry-run", 0, "Print the next N planned scheduler runs and exit, without starting the bot")
+	flag.StringVar(&metricsAddr, "metrics-addr", os.Getenv("METRICS_ADDR"), "Address to serve /metrics, /healthz, and /readyz on, e.g. \":9090\" (disabled if empty)")
 	flag.Parse()
 
 	if bot_token == "" {
@@ -44,28 +76,39 @@ func main() {
 		return
 	}
 
-	if vehicles == "" {
-		slog.Error("Vehicles not provided")
+	if apiURL == "" {
+		slog.Error("RTOC API URL not provided")
 		return
 	}
 
-	if masterIDStr == "" {
-		slog.Error("Master ID not provided")
-		return
+	check.SetAPIURL(apiURL)
+
+	schedulerCfg := check.SchedulerConfig{
+		Times:         splitAndTrim(schedule),
+		Timezone:      timezone,
+		PerVehicleGap: perVehicleGap,
+		Jitter:        jitter,
+		MaxConcurrent: maxConcurrent,
 	}
 
-	masterID, err := strconv.ParseInt(masterIDStr, 10, 64)
-	if err != nil {
-		slog.Error("Invalid master ID", "err", err)
+	if dryRun > 0 {
+		runs, err := schedulerCfg.NextRuns(time.Now(), dryRun)
+		if err != nil {
+			slog.Error("failed to compute schedule", "err", err)
+			return
+		}
+		for _, run := range runs {
+			fmt.Println(run.Format("2006-01-02 15:04:05 MST"))
+		}
 		return
 	}
 
-	if apiURL == "" {
-		slog.Error("RTOC API URL not provided")
+	db, err := store.Open(dbPath)
+	if err != nil {
+		slog.Error("failed to open subscription store", "err", err)
 		return
 	}
-
-	check.SetAPIURL(apiURL)
+	defer db.Close()
 
 	bot, err := tgbotapi.NewBotAPI(bot_token)
 	if err != nil {
@@ -73,46 +116,102 @@ func main() {
 		return
 	}
 
-	vehicleList := check.ParseVehicles(vehicles)
-	slog.Info("loaded vehicles", "count", len(vehicleList), "vehicles", vehicleList)
+	sink, err := buildNotifier(notifiers, bot, webhookURL, discordWebhookURL)
+	if err != nil {
+		slog.Error("failed to build notifier", "err", err)
+		return
+	}
+
+	health := metrics.NewHealth()
 
-	go check.StartScheduler(vehicleList, func(text string) error {
-		msg := tgbotapi.NewMessage(masterID, text)
-		msg.ParseMode = "Markdown"
-		_, err := bot.Send(msg)
-		return err
-	})
+	if metricsAddr != "" {
+		go func() {
+			if err := metrics.Serve(metricsAddr, health); err != nil {
+				slog.Error("metrics server stopped", "err", err)
+			}
+		}()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go check.StartScheduler(ctx, schedulerCfg, db, sink, health)
 
 	u := tgbotapi.NewUpdate(0)
 
 	u.Timeout = 60
 	updates := bot.GetUpdatesChan(u)
 
+	go func() {
+		<-ctx.Done()
+		slog.Info("shutting down, stopping update loop")
+		bot.StopReceivingUpdates()
+	}()
+
 	for update := range updates {
 		if update.Message == nil {
 			continue
 		}
 
-		if update.Message.Chat.ID != masterID {
-			continue
-		}
-
 		if !update.Message.IsCommand() {
 			continue
 		}
 
-		msg := tgbotapi.NewMessage(masterID, "")
+		chatID := update.Message.Chat.ID
+		msg := tgbotapi.NewMessage(chatID, "")
 
 		switch update.Message.Command() {
 		case "start":
 			msg.Text = start_txt
 
 		case "help":
-			msg.Text = `
-			Commands for this @rtocbot are:
-			
-			/start  start the bot
-			/check <REG>  check a specific vehicle e.g. /check T945CEP`
+			msg.Text = help_txt
+
+		case "subscribe":
+			reg := strings.ToUpper(strings.TrimSpace(update.Message.CommandArguments()))
+			if reg == "" {
+				msg.Text = "Usage: /subscribe <REG>"
+				break
+			}
+			if err := db.Subscribe(chatID, reg); err != nil {
+				slog.Error("failed to subscribe", "chat_id", chatID, "registration", reg, "err", err)
+				msg.Text = "❌ Failed to save subscription, try again later."
+				break
+			}
+			msg.Text = fmt.Sprintf("✅ Subscribed to *%s*.", reg)
+			msg.ParseMode = "Markdown"
+
+		case "unsubscribe":
+			reg := strings.ToUpper(strings.TrimSpace(update.Message.CommandArguments()))
+			if reg == "" {
+				msg.Text = "Usage: /unsubscribe <REG>"
+				break
+			}
+			if err := db.Unsubscribe(chatID, reg); err != nil {
+				slog.Error("failed to unsubscribe", "chat_id", chatID, "registration", reg, "err", err)
+				msg.Text = "❌ Failed to remove subscription, try again later."
+				break
+			}
+			msg.Text = fmt.Sprintf("🗑 Unsubscribed from *%s*.", reg)
+			msg.ParseMode = "Markdown"
+
+		case "list":
+			subs, err := db.List(chatID)
+			if err != nil {
+				slog.Error("failed to list subscriptions", "chat_id", chatID, "err", err)
+				msg.Text = "❌ Failed to load your subscriptions, try again later."
+				break
+			}
+			msg.Text = formatSubscriptions(subs)
+			msg.ParseMode = "Markdown"
+
+		case "stop":
+			if err := db.SetNotifyEnabled(chatID, false); err != nil {
+				slog.Error("failed to stop notifications", "chat_id", chatID, "err", err)
+				msg.Text = "❌ Failed to stop notifications, try again later."
+				break
+			}
+			msg.Text = "🔕 Notifications paused. Use /subscribe to resume."
 
 		case "check":
 			args := strings.TrimSpace(update.Message.CommandArguments())
@@ -121,7 +220,20 @@ func main() {
 			if args != "" {
 				regs = []string{strings.ToUpper(args)}
 			} else {
-				regs = vehicleList
+				subs, err := db.List(chatID)
+				if err != nil {
+					slog.Error("failed to list subscriptions", "chat_id", chatID, "err", err)
+					msg.Text = "❌ Failed to load your subscriptions, try again later."
+					break
+				}
+				for _, sub := range subs {
+					regs = append(regs, sub.Registration)
+				}
+			}
+
+			if len(regs) == 0 {
+				msg.Text = "You have no subscribed vehicles. Use /subscribe <REG> or /check <REG>."
+				break
 			}
 
 			msg.Text = fmt.Sprintf("🔎 Checking %d vehicle(s)...", len(regs))
@@ -130,13 +242,15 @@ func main() {
 				slog.Error("failed to send msg", "err", err)
 			}
 
-			go func(registrations []string) {
+			go func(chatID int64, registrations []string) {
 				for i, reg := range registrations {
 					if i > 0 {
-						time.Sleep(10 * time.Minute)
+						if err := ctxSleep(ctx, 10*time.Minute); err != nil {
+							return
+						}
 					}
-					data, err := check.CheckVehicle(reg)
-					reply := tgbotapi.NewMessage(masterID, "")
+					data, err := check.CheckVehicleCtx(ctx, reg)
+					reply := tgbotapi.NewMessage(chatID, "")
 					reply.ParseMode = "Markdown"
 
 					if err != nil {
@@ -145,11 +259,15 @@ func main() {
 						reply.Text = check.FormatResult(reg, data)
 					}
 
-					if _, err := bot.Send(reply); err != nil {
-						slog.Error("failed to send check result", "err", err, "registration", reg)
+					_, sendErr := bot.Send(reply)
+					result := "success"
+					if sendErr != nil {
+						result = "error"
+						slog.Error("failed to send check result", "err", sendErr, "registration", reg)
 					}
+					metrics.NotificationsSentTotal.WithLabelValues("telegram", result).Inc()
 				}
-			}(regs)
+			}(chatID, regs)
 			continue
 
 		default:
@@ -162,3 +280,85 @@ func main() {
 
 	}
 }
+
+func formatSubscriptions(subs []store.Subscription) string {
+	if len(subs) == 0 {
+		return "You have no subscribed vehicles. Use /subscribe <REG> to add one."
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📋 *Your subscriptions:*\n")
+	for _, sub := range subs {
+		status := "🔔"
+		if !sub.NotifyEnabled {
+			status = "🔕"
+		}
+		fmt.Fprintf(&sb, "%s %s (since %s)\n", status, sub.Registration, sub.AddedAt.Format("2006-01-02"))
+	}
+	return sb.String()
+}
+
+// buildNotifier assembles a fan-out check.Notifier from the comma-separated
+// --notifier list, e.g. "telegram,webhook".
+func buildNotifier(spec string, bot *tgbotapi.BotAPI, webhookURL, discordWebhookURL string) (check.Notifier, error) {
+	var sinks []check.Notifier
+
+	for _, name := range strings.Split(spec, ",") {
+		switch strings.TrimSpace(strings.ToLower(name)) {
+		case "telegram":
+			sinks = append(sinks, notify.NewTelegramNotifier(bot))
+		case "webhook":
+			if webhookURL == "" {
+				return nil, fmt.Errorf("--notifier includes webhook but --webhook-url is empty")
+			}
+			sinks = append(sinks, notify.NewWebhookNotifier(webhookURL))
+		case "discord":
+			if discordWebhookURL == "" {
+				return nil, fmt.Errorf("--notifier includes discord but --discord-webhook-url is empty")
+			}
+			sinks = append(sinks, notify.NewDiscordNotifier(discordWebhookURL))
+		case "":
+			// skip empty entries from trailing/leading commas
+		default:
+			return nil, fmt.Errorf("unknown notifier %q", name)
+		}
+	}
+
+	if len(sinks) == 0 {
+		return nil, fmt.Errorf("no notifiers configured")
+	}
+
+	return notify.NewMulti(sinks...), nil
+}
+
+// splitAndTrim splits a comma-separated --schedule value into its
+// individual entries, trimming whitespace and dropping empties.
+func splitAndTrim(s string) []string {
+	parts := strings.Split(s, ",")
+	result := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if v := strings.TrimSpace(p); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// ctxSleep waits for d or until ctx is cancelled, whichever comes first.
+func ctxSleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
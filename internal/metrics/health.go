@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Health tracks scheduler liveness for /healthz and /readyz: when it last
+// ran, when it last completed without error, and what the last error was.
+type Health struct {
+	mu            sync.Mutex
+	lastRunAt     time.Time
+	lastSuccessAt time.Time
+	lastErr       error
+}
+
+// NewHealth returns an empty Health tracker.
+func NewHealth() *Health {
+	return &Health{}
+}
+
+// RecordRun records that a scheduler run just completed, with err nil on
+// success. check.StartScheduler calls it after every run.
+func (h *Health) RecordRun(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	h.lastRunAt = now
+	h.lastErr = err
+	if err == nil {
+		h.lastSuccessAt = now
+	}
+}
+
+type healthStatus struct {
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+func (h *Health) snapshot() healthStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var s healthStatus
+	if !h.lastRunAt.IsZero() {
+		t := h.lastRunAt
+		s.LastRunAt = &t
+	}
+	if !h.lastSuccessAt.IsZero() {
+		t := h.lastSuccessAt
+		s.LastSuccessAt = &t
+	}
+	if h.lastErr != nil {
+		s.Error = h.lastErr.Error()
+	}
+	return s
+}
+
+// LivezHandler always responds 200 with status ok while the process is
+// serving HTTP, and includes the scheduler's last run and last successful
+// run timestamps (and last error, if any) so its liveness can be judged.
+func (h *Health) LivezHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, struct {
+		Status string `json:"status"`
+		healthStatus
+	}{Status: "ok", healthStatus: h.snapshot()})
+}
+
+// ReadyzHandler reports unready until the scheduler has completed at least
+// one successful run.
+func (h *Health) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	s := h.snapshot()
+	if s.LastSuccessAt == nil {
+		writeJSON(w, http.StatusServiceUnavailable, s)
+		return
+	}
+	writeJSON(w, http.StatusOK, s)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
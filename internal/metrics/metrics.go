@@ -0,0 +1,46 @@
+// Package metrics exposes Prometheus instrumentation and health endpoints
+// for running the bot as a long-lived service.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// ChecksTotal counts RTOC API checks by registration and outcome
+	// ("success" or "error").
+	ChecksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rtoc_checks_total",
+		Help: "Total RTOC API checks performed, by registration and result.",
+	}, []string{"registration", "result"})
+
+	// CheckDuration tracks how long RTOC API checks take, including
+	// retries, by registration.
+	CheckDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rtoc_check_duration_seconds",
+		Help:    "Duration of RTOC API checks in seconds, including retries.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"registration"})
+
+	// PendingOffences is the number of pending offences found in the most
+	// recent successful check, by registration.
+	PendingOffences = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rtoc_pending_offences",
+		Help: "Number of pending offences found in the last successful check, by registration.",
+	}, []string{"registration"})
+
+	// InspectionRecords is the number of inspection records found in the
+	// most recent successful check, by registration.
+	InspectionRecords = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rtoc_inspection_records",
+		Help: "Number of inspection records found in the last successful check, by registration.",
+	}, []string{"registration"})
+
+	// NotificationsSentTotal counts notification delivery attempts by
+	// channel ("telegram", "webhook", "discord", ...) and outcome.
+	NotificationsSentTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rtoc_notifications_sent_total",
+		Help: "Total notifications sent, by channel and result.",
+	}, []string{"channel", "result"})
+)
@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Serve starts a blocking HTTP server on addr exposing /metrics,
+// /healthz, and /readyz. Run it in a goroutine from main.
+func Serve(addr string, health *Health) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", health.LivezHandler)
+	mux.HandleFunc("/readyz", health.ReadyzHandler)
+
+	return http.ListenAndServe(addr, mux)
+}
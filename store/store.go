@@ -0,0 +1,218 @@
+// Package store provides a persistent, per-chat subscription registry for
+// the bot so notifications can fan out to many Telegram users instead of a
+// single MASTER_ID.
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Hopertz/rtocBot/check"
+	_ "modernc.org/sqlite"
+)
+
+// Subscription is one chat's subscription to a single vehicle registration.
+type Subscription struct {
+	ChatID        int64
+	Registration  string
+	AddedAt       time.Time
+	NotifyEnabled bool
+}
+
+// Store wraps a SQLite-backed subscription table.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (creating if necessary) the SQLite database at path and runs
+// the store's migrations. WAL mode and a busy timeout are enabled so
+// concurrent readers/writers (e.g. checkAllVehicles with MaxConcurrent > 1)
+// block briefly on a busy database instead of failing with SQLITE_BUSY.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+
+	s := &Store{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	return s, nil
+}
+
+func (s *Store) migrate() error {
+	const schema = `
+CREATE TABLE IF NOT EXISTS subscriptions (
+	chat_id        INTEGER NOT NULL,
+	registration   TEXT NOT NULL,
+	added_at       TIMESTAMP NOT NULL,
+	notify_enabled INTEGER NOT NULL DEFAULT 1,
+	PRIMARY KEY (chat_id, registration)
+);
+
+CREATE TABLE IF NOT EXISTS snapshots (
+	registration  TEXT PRIMARY KEY,
+	response_json TEXT NOT NULL,
+	updated_at    TIMESTAMP NOT NULL
+);
+`
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Subscribe records that chatID wants notifications for registration,
+// re-enabling notifications if the pair already existed.
+func (s *Store) Subscribe(chatID int64, registration string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO subscriptions (chat_id, registration, added_at, notify_enabled)
+		 VALUES (?, ?, ?, 1)
+		 ON CONFLICT(chat_id, registration) DO UPDATE SET notify_enabled = 1`,
+		chatID, registration, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("subscribe %s for chat %d: %w", registration, chatID, err)
+	}
+	return nil
+}
+
+// Unsubscribe removes chatID's subscription to registration entirely.
+func (s *Store) Unsubscribe(chatID int64, registration string) error {
+	_, err := s.db.Exec(`DELETE FROM subscriptions WHERE chat_id = ? AND registration = ?`, chatID, registration)
+	if err != nil {
+		return fmt.Errorf("unsubscribe %s for chat %d: %w", registration, chatID, err)
+	}
+	return nil
+}
+
+// SetNotifyEnabled toggles notifications for every subscription belonging to
+// chatID, without deleting them. This backs the /stop command.
+func (s *Store) SetNotifyEnabled(chatID int64, enabled bool) error {
+	_, err := s.db.Exec(`UPDATE subscriptions SET notify_enabled = ? WHERE chat_id = ?`, enabled, chatID)
+	if err != nil {
+		return fmt.Errorf("set notify enabled for chat %d: %w", chatID, err)
+	}
+	return nil
+}
+
+// List returns chatID's subscriptions, oldest first.
+func (s *Store) List(chatID int64) ([]Subscription, error) {
+	rows, err := s.db.Query(
+		`SELECT chat_id, registration, added_at, notify_enabled
+		 FROM subscriptions WHERE chat_id = ? ORDER BY added_at`,
+		chatID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list subscriptions for chat %d: %w", chatID, err)
+	}
+	defer rows.Close()
+	return scanSubscriptions(rows)
+}
+
+// AllRegistrations returns the deduped union of registrations that have at
+// least one chat subscribed with notifications enabled. StartScheduler uses
+// this so it checks each plate once per run regardless of subscriber count.
+func (s *Store) AllRegistrations() ([]string, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT registration FROM subscriptions WHERE notify_enabled = 1 ORDER BY registration`)
+	if err != nil {
+		return nil, fmt.Errorf("list all registrations: %w", err)
+	}
+	defer rows.Close()
+
+	var regs []string
+	for rows.Next() {
+		var reg string
+		if err := rows.Scan(&reg); err != nil {
+			return nil, fmt.Errorf("scan registration: %w", err)
+		}
+		regs = append(regs, reg)
+	}
+	return regs, rows.Err()
+}
+
+// SubscribersFor returns the chat IDs subscribed to registration with
+// notifications enabled, so a scheduler run can fan results out only to
+// interested chats.
+func (s *Store) SubscribersFor(registration string) ([]int64, error) {
+	rows, err := s.db.Query(
+		`SELECT chat_id FROM subscriptions WHERE registration = ? AND notify_enabled = 1`,
+		registration,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list subscribers for %s: %w", registration, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan chat id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// LastSnapshot returns the most recently saved APIResponse for registration,
+// or nil if none has been saved yet.
+func (s *Store) LastSnapshot(registration string) (*check.APIResponse, error) {
+	var raw string
+	err := s.db.QueryRow(`SELECT response_json FROM snapshots WHERE registration = ?`, registration).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load snapshot for %s: %w", registration, err)
+	}
+
+	var data check.APIResponse
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		return nil, fmt.Errorf("unmarshal snapshot for %s: %w", registration, err)
+	}
+	return &data, nil
+}
+
+// SaveSnapshot persists data as the latest APIResponse seen for
+// registration, overwriting any previous snapshot.
+func (s *Store) SaveSnapshot(registration string, data *check.APIResponse) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot for %s: %w", registration, err)
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO snapshots (registration, response_json, updated_at)
+		 VALUES (?, ?, ?)
+		 ON CONFLICT(registration) DO UPDATE SET response_json = excluded.response_json, updated_at = excluded.updated_at`,
+		registration, string(raw), time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("save snapshot for %s: %w", registration, err)
+	}
+	return nil
+}
+
+func scanSubscriptions(rows *sql.Rows) ([]Subscription, error) {
+	var subs []Subscription
+	for rows.Next() {
+		var sub Subscription
+		var notify int
+		if err := rows.Scan(&sub.ChatID, &sub.Registration, &sub.AddedAt, &notify); err != nil {
+			return nil, fmt.Errorf("scan subscription: %w", err)
+		}
+		sub.NotifyEnabled = notify != 0
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
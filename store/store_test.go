@@ -0,0 +1,219 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Hopertz/rtocBot/check"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "rtocbot.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSubscribeAndList(t *testing.T) {
+	s := openTestStore(t)
+
+	if err := s.Subscribe(1, "T123ABC"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := s.Subscribe(1, "T456DEF"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	subs, err := s.List(1)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(subs) != 2 {
+		t.Fatalf("len(subs) = %d, want 2", len(subs))
+	}
+	if subs[0].Registration != "T123ABC" || subs[1].Registration != "T456DEF" {
+		t.Fatalf("subs = %+v, want [T123ABC T456DEF] oldest first", subs)
+	}
+	for _, sub := range subs {
+		if !sub.NotifyEnabled {
+			t.Errorf("sub %+v NotifyEnabled = false, want true", sub)
+		}
+	}
+}
+
+func TestUnsubscribeRemovesSubscription(t *testing.T) {
+	s := openTestStore(t)
+
+	if err := s.Subscribe(1, "T123ABC"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := s.Unsubscribe(1, "T123ABC"); err != nil {
+		t.Fatalf("Unsubscribe() error = %v", err)
+	}
+
+	subs, err := s.List(1)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(subs) != 0 {
+		t.Fatalf("len(subs) = %d, want 0", len(subs))
+	}
+}
+
+func TestSetNotifyEnabledTogglesWithoutDeleting(t *testing.T) {
+	s := openTestStore(t)
+
+	if err := s.Subscribe(1, "T123ABC"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := s.Subscribe(1, "T456DEF"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	if err := s.SetNotifyEnabled(1, false); err != nil {
+		t.Fatalf("SetNotifyEnabled(false) error = %v", err)
+	}
+
+	subs, err := s.List(1)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(subs) != 2 {
+		t.Fatalf("len(subs) = %d, want 2 (toggled off, not deleted)", len(subs))
+	}
+	for _, sub := range subs {
+		if sub.NotifyEnabled {
+			t.Errorf("sub %+v NotifyEnabled = true, want false", sub)
+		}
+	}
+
+	regs, err := s.AllRegistrations()
+	if err != nil {
+		t.Fatalf("AllRegistrations() error = %v", err)
+	}
+	if len(regs) != 0 {
+		t.Fatalf("AllRegistrations() = %v, want none while notifications are disabled", regs)
+	}
+
+	if err := s.SetNotifyEnabled(1, true); err != nil {
+		t.Fatalf("SetNotifyEnabled(true) error = %v", err)
+	}
+	regs, err = s.AllRegistrations()
+	if err != nil {
+		t.Fatalf("AllRegistrations() error = %v", err)
+	}
+	if len(regs) != 2 {
+		t.Fatalf("AllRegistrations() = %v, want 2 after re-enabling", regs)
+	}
+}
+
+func TestResubscribeReenablesNotifications(t *testing.T) {
+	s := openTestStore(t)
+
+	if err := s.Subscribe(1, "T123ABC"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := s.SetNotifyEnabled(1, false); err != nil {
+		t.Fatalf("SetNotifyEnabled(false) error = %v", err)
+	}
+	if err := s.Subscribe(1, "T123ABC"); err != nil {
+		t.Fatalf("Subscribe() (re-subscribe) error = %v", err)
+	}
+
+	subs, err := s.List(1)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(subs) != 1 || !subs[0].NotifyEnabled {
+		t.Fatalf("subs = %+v, want one re-enabled subscription", subs)
+	}
+}
+
+func TestAllRegistrationsDedupesAcrossChats(t *testing.T) {
+	s := openTestStore(t)
+
+	if err := s.Subscribe(1, "T123ABC"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := s.Subscribe(2, "T123ABC"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := s.Subscribe(2, "T456DEF"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	regs, err := s.AllRegistrations()
+	if err != nil {
+		t.Fatalf("AllRegistrations() error = %v", err)
+	}
+	if len(regs) != 2 {
+		t.Fatalf("AllRegistrations() = %v, want 2 deduped registrations", regs)
+	}
+}
+
+func TestSubscribersForOnlyReturnsEnabledChats(t *testing.T) {
+	s := openTestStore(t)
+
+	if err := s.Subscribe(1, "T123ABC"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := s.Subscribe(2, "T123ABC"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if err := s.SetNotifyEnabled(2, false); err != nil {
+		t.Fatalf("SetNotifyEnabled(false) error = %v", err)
+	}
+
+	ids, err := s.SubscribersFor("T123ABC")
+	if err != nil {
+		t.Fatalf("SubscribersFor() error = %v", err)
+	}
+	if len(ids) != 1 || ids[0] != 1 {
+		t.Fatalf("SubscribersFor() = %v, want [1]", ids)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	s := openTestStore(t)
+
+	existing, err := s.LastSnapshot("T123ABC")
+	if err != nil {
+		t.Fatalf("LastSnapshot() error = %v", err)
+	}
+	if existing != nil {
+		t.Fatalf("LastSnapshot() = %+v, want nil before any save", existing)
+	}
+
+	data := &check.APIResponse{
+		Status: "ok",
+		PendingTransactions: []check.PendingTransaction{
+			{Reference: "A1", Offence: "speeding", Status: "unpaid"},
+		},
+	}
+	if err := s.SaveSnapshot("T123ABC", data); err != nil {
+		t.Fatalf("SaveSnapshot() error = %v", err)
+	}
+
+	got, err := s.LastSnapshot("T123ABC")
+	if err != nil {
+		t.Fatalf("LastSnapshot() error = %v", err)
+	}
+	if got == nil || len(got.PendingTransactions) != 1 || got.PendingTransactions[0].Reference != "A1" {
+		t.Fatalf("LastSnapshot() = %+v, want round-tripped data", got)
+	}
+
+	updated := &check.APIResponse{Status: "ok"}
+	if err := s.SaveSnapshot("T123ABC", updated); err != nil {
+		t.Fatalf("SaveSnapshot() (overwrite) error = %v", err)
+	}
+	got, err = s.LastSnapshot("T123ABC")
+	if err != nil {
+		t.Fatalf("LastSnapshot() error = %v", err)
+	}
+	if got == nil || len(got.PendingTransactions) != 0 {
+		t.Fatalf("LastSnapshot() = %+v, want overwritten snapshot with no transactions", got)
+	}
+}